@@ -0,0 +1,128 @@
+package cgminerapi
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"testing"
+)
+
+// fakeMiner listens once per accepted connection, decodes the APICommand it's
+// sent, and replies with whatever handle returns, mimicking stock cgminer's
+// one-shot-per-connection behavior.
+func fakeMiner(t *testing.T, handle func(APICommand) string) *APIClient {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				var cmd APICommand
+				if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&cmd); err != nil {
+					return
+				}
+				conn.Write([]byte(handle(cmd)))
+			}()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	return NewCgminerAPI(host, port)
+}
+
+func TestAuthenticateHashesSaltAndPassword(t *testing.T) {
+	const salt = "abc123"
+	client := fakeMiner(t, func(cmd APICommand) string {
+		if cmd.Method != "token" {
+			t.Errorf("authenticate sent method %q, want %q", cmd.Method, "token")
+		}
+		return `{"STATUS":[{"STATUS":"S","Description":"` + salt + `"}]}`
+	})
+	client.Password = "hunter2"
+
+	got, err := client.authenticate()
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte(salt + client.Password))
+	want := hex.EncodeToString(sum[:])
+	if got != want {
+		t.Errorf("authenticate() = %q, want %q", got, want)
+	}
+}
+
+func TestSendPrivilegedSkipsAuthWithoutPassword(t *testing.T) {
+	var gotMethod, gotParameter string
+	client := fakeMiner(t, func(cmd APICommand) string {
+		gotMethod, gotParameter = cmd.Method, cmd.Parameter
+		return `{"STATUS":[{"STATUS":"S"}]}`
+	})
+
+	if _, err := client.sendPrivileged(&APICommand{Method: "restart"}); err != nil {
+		t.Fatalf("sendPrivileged: %v", err)
+	}
+	if gotMethod != "restart" {
+		t.Errorf("method = %q, want %q", gotMethod, "restart")
+	}
+	if gotParameter != "" {
+		t.Errorf("parameter = %q, want empty (no auth round trip without a password)", gotParameter)
+	}
+}
+
+func TestSendPrivilegedPrefixesHashWithPassword(t *testing.T) {
+	const salt = "deadbeef"
+	calls := 0
+	var gotParameter string
+	client := fakeMiner(t, func(cmd APICommand) string {
+		calls++
+		if cmd.Method == "token" {
+			return `{"STATUS":[{"STATUS":"S","Description":"` + salt + `"}]}`
+		}
+		gotParameter = cmd.Parameter
+		return `{"STATUS":[{"STATUS":"S"}]}`
+	})
+	client.Password = "hunter2"
+
+	if _, err := client.sendPrivileged(&APICommand{Method: "enablepool", Parameter: "0"}); err != nil {
+		t.Fatalf("sendPrivileged: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte(salt + client.Password))
+	hash := hex.EncodeToString(sum[:])
+	want := hash + ",0"
+	if gotParameter != want {
+		t.Errorf("parameter = %q, want %q", gotParameter, want)
+	}
+	if calls != 2 {
+		t.Errorf("miner saw %d commands, want 2 (token, then the authenticated command)", calls)
+	}
+}
+
+func TestSendPrivilegedPassesThroughReadOnlyMethods(t *testing.T) {
+	calls := 0
+	client := fakeMiner(t, func(cmd APICommand) string {
+		calls++
+		return `{"STATUS":[{"STATUS":"S"}]}`
+	})
+	client.Password = "hunter2"
+
+	if _, err := client.sendPrivileged(&APICommand{Method: "summary"}); err != nil {
+		t.Fatalf("sendPrivileged: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("miner saw %d commands, want 1 (no auth round trip for a non-privileged method)", calls)
+	}
+}
@@ -1,36 +1,31 @@
 /*
 Package cgminerapi provides a client for using the cgminer API.
 
-Construct a new cgminer client, then use the various services on the client to
-access different parts of the cgminer RPC API. For example:
+Construct a new cgminer client, then call the typed methods on it to access
+the different parts of the cgminer RPC API. For example:
 
 	client := cgminerapi.NewCgminerAPI("localhost", "4028")
 
-	command := cgminerapi.APICommand{Method: "summary"}
-	resp, err := api.Send(&command)
+	summary, err := client.Summary()
+	pools, err := client.Pools()
+	err = client.EnablePool(0)
 
-Set optional parameters for an method using an APICommand's Parameter field.
+Send remains available as a lower-level escape hatch for commands that don't
+yet have a typed method, or for inspecting the raw Response.
 
 	command := cgminerapi.APICommand{Method: "gpu", Parameter: "0"}
-	resp, err := api.Send(&command)
+	resp, err := client.Send(&command)
+
+Managing a fleet of vendor-forked firmware (Antminer, Innosilicon,
+Whatsminer, ...)? Use NewCgminerAPIAutoDetect, or pin a Driver on the client
+directly, so Summary and ChipStat speak that firmware's dialect.
 */
 package cgminerapi
 
 import (
-	"bytes"
 	"encoding/json"
-	"errors"
-	"io"
-	"io/ioutil"
-	"log"
-	"net"
 )
 
-// APIClient stores connection details.
-type APIClient struct {
-	Host, Port string
-}
-
 type APIStatus struct {
 	Code        int    `json:"Code,omitempty"`
 	Description string `json:"Description,omitempty"`
@@ -39,13 +34,21 @@ type APIStatus struct {
 	When        int    `json:"When,omitempty"`
 }
 
-// Response holds the various possible API response fields.
+// Response holds the various possible API response fields. It is kept around
+// as the return type of the low-level Send escape hatch; the typed methods on
+// APIClient (Summary, Devs, Pools, ...) unmarshal into their own per-command
+// structs instead of sharing this one blob.
 type Response struct {
 	Status  []APIStatus `json:"STATUS"`
 	Summary []Summary   `json:"SUMMARY,omitempty"`
 	Config  []Config    `json:"CONFIG,omitempty"`
 	Devs    []Devs      `json:"DEVS,omitempty"`
 	Gpu     []Devs      `json:"GPU,omitempty"`
+	Pools   []Pool      `json:"POOLS,omitempty"`
+	Version []Version   `json:"VERSION,omitempty"`
+	Coin    []Coin      `json:"COIN,omitempty"`
+	Notify  []Notify    `json:"NOTIFY,omitempty"`
+	Stats   []ChipStats `json:"STATS,omitempty"`
 }
 
 type Summary struct {
@@ -137,62 +140,7 @@ type APICommand struct {
 	Parameter string `json:"parameter,omitempty"`
 }
 
-// NewCgminerAPI returns a pointer to an APIClient with the specified host and port.
-func NewCgminerAPI(host string, port string) *APIClient {
-	return &APIClient{host, port}
-}
-
-func ReadAll(r io.Reader) (string, error) {
-	b, err := ioutil.ReadAll(r)
-	return string(bytes.Trim(b, " \x00")), err
-}
-
 func Encode(e APIError) string {
 	blob, _ := json.Marshal(e)
 	return string(blob)
 }
-
-// Send sends the APICommand (and any specified parameters) and returns a Response containing
-// the response from the API.
-func (client *APIClient) Send(command *APICommand) (Response, error) {
-	c, err := net.Dial("tcp", client.Host+":"+client.Port)
-	if err != nil {
-		log.Fatal(err)
-		return Response{}, err
-	}
-	defer c.Close()
-
-	blob, err := json.Marshal(command)
-	if err != nil {
-		log.Fatal(err)
-		return Response{}, err
-	}
-
-	_, err = c.Write(blob)
-
-	if err != nil {
-		log.Fatal(err)
-		return Response{}, err
-	}
-
-	jsonstring, err := ReadAll(c)
-	if err != nil {
-		log.Fatal(err)
-		return Response{}, err
-	}
-
-	var resp Response
-	err = json.Unmarshal([]byte(jsonstring), &resp)
-	if err != nil {
-		log.Fatal(err)
-		return Response{}, err
-	}
-
-	switch resp.Status[0].STATUS {
-	case "W", "I", "S":
-		return resp, nil
-	case "E", "F":
-		return Response{}, errors.New(resp.Status[0].Msg)
-	}
-	return Response{}, errors.New("Unknown error")
-}
@@ -0,0 +1,156 @@
+package cgminerapi
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+)
+
+// ChipStats holds a single entry from the STATS response returned by the
+// "stats"/"estats" commands. Beyond the handful of fixed fields, cgminer and
+// its vendor forks (Antminer, Innosilicon, Whatsminer, ...) emit an open-ended
+// set of numerically-indexed per-chip/per-chain fields such as "1_accept",
+// "2_reject", "chain_rate3" or "temp2_1". UnmarshalJSON sorts those into the
+// maps below, keyed by chip or chain index, instead of dropping them.
+type ChipStats struct {
+	ASC     int
+	PGA     int
+	Name    string
+	ID      int
+	Elapsed float64
+
+	// Accept, Reject and Noise hold per-chip counters from keys of the form
+	// "<chip>_accept", "<chip>_reject" and "<chip>_noise".
+	Accept map[int]int64
+	Reject map[int]int64
+	Noise  map[int]int64
+
+	// ChainRate holds per-chain hashrate from keys of the form
+	// "chain_rate<chain>".
+	ChainRate map[int]float64
+
+	// ChainTemp holds per-chain, per-chip temperature readings from keys of
+	// the form "temp<chain>_<chip>", indexed [chain][chip].
+	ChainTemp map[int][]float64
+}
+
+var (
+	chipCounterKey = regexp.MustCompile(`^(\d+)_(accept|reject|noise)$`)
+	chainRateKey   = regexp.MustCompile(`^chain_rate(\d+)$`)
+	chainTempKey   = regexp.MustCompile(`^temp(\d+)_(\d+)$`)
+)
+
+// UnmarshalJSON decodes a STATS entry, tolerating string-encoded numbers and
+// ignoring fields it doesn't recognize so that an unfamiliar vendor alias
+// doesn't fail the whole decode.
+func (c *ChipStats) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	c.Accept = map[int]int64{}
+	c.Reject = map[int]int64{}
+	c.Noise = map[int]int64{}
+	c.ChainRate = map[int]float64{}
+	c.ChainTemp = map[int][]float64{}
+
+	for key, value := range raw {
+		switch key {
+		case "ASC":
+			c.ASC = int(looseNumber(value))
+			continue
+		case "PGA":
+			c.PGA = int(looseNumber(value))
+			continue
+		case "ID":
+			c.ID = int(looseNumber(value))
+			continue
+		case "Elapsed":
+			c.Elapsed = looseNumber(value)
+			continue
+		case "Name":
+			json.Unmarshal(value, &c.Name)
+			continue
+		}
+
+		if m := chipCounterKey.FindStringSubmatch(key); m != nil {
+			idx, _ := strconv.Atoi(m[1])
+			n := int64(looseNumber(value))
+			switch m[2] {
+			case "accept":
+				c.Accept[idx] = n
+			case "reject":
+				c.Reject[idx] = n
+			case "noise":
+				c.Noise[idx] = n
+			}
+			continue
+		}
+
+		if m := chainRateKey.FindStringSubmatch(key); m != nil {
+			idx, _ := strconv.Atoi(m[1])
+			c.ChainRate[idx] = looseNumber(value)
+			continue
+		}
+
+		if m := chainTempKey.FindStringSubmatch(key); m != nil {
+			chain, _ := strconv.Atoi(m[1])
+			chip, _ := strconv.Atoi(m[2])
+			temps := c.ChainTemp[chain]
+			for len(temps) <= chip {
+				temps = append(temps, 0)
+			}
+			temps[chip] = looseNumber(value)
+			c.ChainTemp[chain] = temps
+			continue
+		}
+
+		// Unrecognized or vendor-specific alias: ignore rather than fail.
+	}
+
+	return nil
+}
+
+// looseNumber decodes a JSON number that may have been sent as a quoted
+// string, as cgminer and some vendor forks do inconsistently. It returns 0
+// if value is neither.
+func looseNumber(value json.RawMessage) float64 {
+	var f float64
+	if err := json.Unmarshal(value, &f); err == nil {
+		return f
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return 0
+}
+
+// Stats runs the "stats" command and returns the per-device chip/chain
+// statistics, including any vendor-specific dynamic fields.
+func (client *APIClient) Stats() ([]ChipStats, error) {
+	resp, err := client.Send(&APICommand{Method: "stats"})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Stats, nil
+}
+
+// ChipStat returns the chip/chain statistics for device n, via client's
+// Driver if one is set (see DetectDriver), or GenericCGMiner otherwise.
+func (client *APIClient) ChipStat(n int) (ChipStats, error) {
+	return client.driver().ChipStat(client, n)
+}
+
+// EStats runs the "estats" command, cgminer's extended-stats variant, and
+// returns the per-device chip/chain statistics.
+func (client *APIClient) EStats() ([]ChipStats, error) {
+	resp, err := client.Send(&APICommand{Method: "estats"})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Stats, nil
+}
@@ -0,0 +1,99 @@
+package cgminerapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestChipStatsUnmarshalJSON(t *testing.T) {
+	data := []byte(`{
+		"ASC": 0,
+		"ID": "0",
+		"Elapsed": "123.5",
+		"Name": "bitmain-s9",
+		"1_accept": 154,
+		"2_accept": "132",
+		"1_reject": 2,
+		"1_noise": 0,
+		"chain_rate1": 4.71,
+		"chain_rate2": "4.69",
+		"temp1_1": 63,
+		"temp1_2": 65,
+		"temp2_1": 61,
+		"frequency": 625,
+		"miner_version": "1.2.3"
+	}`)
+
+	var stat ChipStats
+	if err := json.Unmarshal(data, &stat); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if stat.ASC != 0 {
+		t.Errorf("ASC = %d, want 0", stat.ASC)
+	}
+	if stat.ID != 0 {
+		t.Errorf("ID = %d, want 0 (string-encoded)", stat.ID)
+	}
+	if stat.Elapsed != 123.5 {
+		t.Errorf("Elapsed = %v, want 123.5 (string-encoded)", stat.Elapsed)
+	}
+	if stat.Name != "bitmain-s9" {
+		t.Errorf("Name = %q, want bitmain-s9", stat.Name)
+	}
+
+	wantAccept := map[int]int64{1: 154, 2: 132}
+	if !reflect.DeepEqual(stat.Accept, wantAccept) {
+		t.Errorf("Accept = %v, want %v", stat.Accept, wantAccept)
+	}
+	wantReject := map[int]int64{1: 2}
+	if !reflect.DeepEqual(stat.Reject, wantReject) {
+		t.Errorf("Reject = %v, want %v", stat.Reject, wantReject)
+	}
+	wantNoise := map[int]int64{1: 0}
+	if !reflect.DeepEqual(stat.Noise, wantNoise) {
+		t.Errorf("Noise = %v, want %v", stat.Noise, wantNoise)
+	}
+
+	wantChainRate := map[int]float64{1: 4.71, 2: 4.69}
+	if !reflect.DeepEqual(stat.ChainRate, wantChainRate) {
+		t.Errorf("ChainRate = %v, want %v", stat.ChainRate, wantChainRate)
+	}
+
+	// Chip indices are used directly as slice positions, so a 1-indexed chip
+	// leaves a zero-valued placeholder at position 0.
+	wantChainTemp := map[int][]float64{1: {0, 63, 65}, 2: {0, 61}}
+	if !reflect.DeepEqual(stat.ChainTemp, wantChainTemp) {
+		t.Errorf("ChainTemp = %v, want %v", stat.ChainTemp, wantChainTemp)
+	}
+}
+
+func TestChipStatsUnmarshalJSONEmpty(t *testing.T) {
+	var stat ChipStats
+	if err := json.Unmarshal([]byte(`{}`), &stat); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(stat.Accept) != 0 || len(stat.ChainRate) != 0 || len(stat.ChainTemp) != 0 {
+		t.Errorf("expected empty maps for an entry with no dynamic fields, got %+v", stat)
+	}
+}
+
+func TestLooseNumber(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want float64
+	}{
+		{`42`, 42},
+		{`4.5`, 4.5},
+		{`"42"`, 42},
+		{`"4.5"`, 4.5},
+		{`"not-a-number"`, 0},
+		{`null`, 0},
+	}
+	for _, c := range cases {
+		if got := looseNumber(json.RawMessage(c.raw)); got != c.want {
+			t.Errorf("looseNumber(%s) = %v, want %v", c.raw, got, c.want)
+		}
+	}
+}
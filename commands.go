@@ -0,0 +1,236 @@
+package cgminerapi
+
+import "fmt"
+
+// Pool describes a single entry from the POOLS response.
+type Pool struct {
+	Accepted            int     `json:"Accepted,omitempty"`
+	BestShare           float64 `json:"Best Share,omitempty"`
+	Diff1Shares         float64 `json:"Diff1 Shares,omitempty"`
+	DifficultyAccepted  float64 `json:"Difficulty Accepted,omitempty"`
+	DifficultyRejected  float64 `json:"Difficulty Rejected,omitempty"`
+	DifficultyStale     float64 `json:"Difficulty Stale,omitempty"`
+	Discarded           float64 `json:"Discarded,omitempty"`
+	GetFailures         float64 `json:"Get Failures,omitempty"`
+	Getworks            float64 `json:"Getworks,omitempty"`
+	HasGBT              bool    `json:"Has GBT,omitempty"`
+	HasStratum          bool    `json:"Has Stratum,omitempty"`
+	LastShareDifficulty float64 `json:"Last Share Difficulty,omitempty"`
+	LastShareTime       float64 `json:"Last Share Time,omitempty"`
+	LongPoll            string  `json:"Long Poll,omitempty"`
+	POOL                int     `json:"POOL,omitempty"`
+	Priority            int     `json:"Priority,omitempty"`
+	ProxyType           string  `json:"Proxy Type,omitempty"`
+	Proxy               string  `json:"Proxy,omitempty"`
+	Quota               int     `json:"Quota,omitempty"`
+	Rejected            int     `json:"Rejected,omitempty"`
+	RemoteFailures      float64 `json:"Remote Failures,omitempty"`
+	Stale               float64 `json:"Stale,omitempty"`
+	Status              string  `json:"Status,omitempty"`
+	StratumActive       bool    `json:"Stratum Active,omitempty"`
+	StratumURL          string  `json:"Stratum URL,omitempty"`
+	URL                 string  `json:"URL,omitempty"`
+	User                string  `json:"User,omitempty"`
+	WorkDifficulty      float64 `json:"Work Difficulty,omitempty"`
+}
+
+// Version describes the VERSION response, reporting the API protocol
+// version alongside the miner's own build information.
+type Version struct {
+	API     string `json:"API,omitempty"`
+	CGMiner string `json:"CGMiner,omitempty"`
+	Miner   string `json:"Miner,omitempty"`
+	Type    string `json:"Type,omitempty"`
+}
+
+// Coin describes the COIN response.
+type Coin struct {
+	Hashmethod        string  `json:"Hash Method,omitempty"`
+	CurrentBlockTime  float64 `json:"Current Block Time,omitempty"`
+	CurrentBlockHash  string  `json:"Current Block Hash,omitempty"`
+	LP                bool    `json:"LP,omitempty"`
+	NetworkDifficulty float64 `json:"Network Difficulty,omitempty"`
+}
+
+// Notify describes a single device entry from the NOTIFY response, which
+// reports how often and why a device has dropped out of "well" status.
+type Notify struct {
+	ASC            int     `json:"ASC,omitempty"`
+	PGA            int     `json:"PGA,omitempty"`
+	Name           string  `json:"Name,omitempty"`
+	ID             int     `json:"ID,omitempty"`
+	LastNotWell    float64 `json:"Last Not Well,omitempty"`
+	LastWellReason int     `json:"Last Well Reason,omitempty"`
+	Reason         string  `json:"Reason,omitempty"`
+	ThisMinutes    int     `json:"This Minutes,omitempty"`
+	ThisReason     int     `json:"This Reason,omitempty"`
+	TotalMinutes   int     `json:"Total Minutes,omitempty"`
+	TotalReason    int     `json:"Total Reason,omitempty"`
+}
+
+// Summary returns the miner's aggregate hashrate and share statistics,
+// via client's Driver if one is set (see DetectDriver), or GenericCGMiner
+// otherwise.
+func (client *APIClient) Summary() (Summary, error) {
+	return client.driver().Summary(client)
+}
+
+// Devs runs the "devs" command and returns the per-device statistics.
+func (client *APIClient) Devs() ([]Devs, error) {
+	resp, err := client.Send(&APICommand{Method: "devs"})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Devs, nil
+}
+
+// Pools runs the "pools" command and returns the configured pools.
+func (client *APIClient) Pools() ([]Pool, error) {
+	resp, err := client.Send(&APICommand{Method: "pools"})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Pools, nil
+}
+
+// Config runs the "config" command and returns the miner's running
+// configuration.
+func (client *APIClient) Config() (Config, error) {
+	resp, err := client.Send(&APICommand{Method: "config"})
+	if err != nil {
+		return Config{}, err
+	}
+	if len(resp.Config) == 0 {
+		return Config{}, errNoData("config", "CONFIG")
+	}
+	return resp.Config[0], nil
+}
+
+// Version runs the "version" command and returns the API and miner
+// version information.
+func (client *APIClient) Version() (Version, error) {
+	resp, err := client.Send(&APICommand{Method: "version"})
+	if err != nil {
+		return Version{}, err
+	}
+	if len(resp.Version) == 0 {
+		return Version{}, errNoData("version", "VERSION")
+	}
+	return resp.Version[0], nil
+}
+
+// Coin runs the "coin" command and returns information about the
+// currently mined coin.
+func (client *APIClient) Coin() (Coin, error) {
+	resp, err := client.Send(&APICommand{Method: "coin"})
+	if err != nil {
+		return Coin{}, err
+	}
+	if len(resp.Coin) == 0 {
+		return Coin{}, errNoData("coin", "COIN")
+	}
+	return resp.Coin[0], nil
+}
+
+// Notify runs the "notify" command and returns the per-device failure
+// history cgminer tracks internally.
+func (client *APIClient) Notify() ([]Notify, error) {
+	resp, err := client.Send(&APICommand{Method: "notify"})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Notify, nil
+}
+
+// AddPool adds a pool to the miner's pool list.
+func (client *APIClient) AddPool(url, user, pass string) error {
+	_, err := client.sendPrivileged(&APICommand{
+		Method:    "addpool",
+		Parameter: fmt.Sprintf("%s,%s,%s", url, user, pass),
+	})
+	return err
+}
+
+// SwitchPool switches the active pool to pool n.
+func (client *APIClient) SwitchPool(n int) error {
+	_, err := client.Send(&APICommand{Method: "switchpool", Parameter: fmt.Sprint(n)})
+	return err
+}
+
+// EnablePool enables pool n.
+func (client *APIClient) EnablePool(n int) error {
+	_, err := client.sendPrivileged(&APICommand{Method: "enablepool", Parameter: fmt.Sprint(n)})
+	return err
+}
+
+// DisablePool disables pool n.
+func (client *APIClient) DisablePool(n int) error {
+	_, err := client.sendPrivileged(&APICommand{Method: "disablepool", Parameter: fmt.Sprint(n)})
+	return err
+}
+
+// RemovePool removes pool n from the miner's pool list.
+func (client *APIClient) RemovePool(n int) error {
+	_, err := client.sendPrivileged(&APICommand{Method: "removepool", Parameter: fmt.Sprint(n)})
+	return err
+}
+
+// GPUEnable enables GPU n.
+func (client *APIClient) GPUEnable(n int) error {
+	_, err := client.sendPrivileged(&APICommand{Method: "gpuenable", Parameter: fmt.Sprint(n)})
+	return err
+}
+
+// GPUDisable disables GPU n.
+func (client *APIClient) GPUDisable(n int) error {
+	_, err := client.Send(&APICommand{Method: "gpudisable", Parameter: fmt.Sprint(n)})
+	return err
+}
+
+// Restart asks cgminer to restart itself.
+func (client *APIClient) Restart() error {
+	_, err := client.sendPrivileged(&APICommand{Method: "restart"})
+	return err
+}
+
+// Quit asks cgminer to shut down.
+func (client *APIClient) Quit() error {
+	_, err := client.sendPrivileged(&APICommand{Method: "quit"})
+	return err
+}
+
+// Save asks cgminer to write its running configuration to file.
+func (client *APIClient) Save(file string) error {
+	_, err := client.sendPrivileged(&APICommand{Method: "save", Parameter: file})
+	return err
+}
+
+// PGA returns the device statistics for FPGA n.
+func (client *APIClient) PGA(n int) (Devs, error) {
+	resp, err := client.Send(&APICommand{Method: "pga", Parameter: fmt.Sprint(n)})
+	if err != nil {
+		return Devs{}, err
+	}
+	if len(resp.Devs) == 0 {
+		return Devs{}, errNoData("pga", "DEVS")
+	}
+	return resp.Devs[0], nil
+}
+
+// ASC returns the device statistics for ASIC n.
+func (client *APIClient) ASC(n int) (Devs, error) {
+	resp, err := client.Send(&APICommand{Method: "asc", Parameter: fmt.Sprint(n)})
+	if err != nil {
+		return Devs{}, err
+	}
+	if len(resp.Devs) == 0 {
+		return Devs{}, errNoData("asc", "DEVS")
+	}
+	return resp.Devs[0], nil
+}
+
+// errNoData reports that method ran without error but didn't return the
+// section of the response it was expected to populate.
+func errNoData(method, section string) error {
+	return fmt.Errorf("cgminerapi: %q command returned no %s data", method, section)
+}
@@ -0,0 +1,68 @@
+package cgminerapi
+
+import "testing"
+
+func TestDevs(t *testing.T) {
+	client := fakeMiner(t, func(cmd APICommand) string {
+		if cmd.Method != "devs" {
+			t.Errorf("method = %q, want %q", cmd.Method, "devs")
+		}
+		return `{"STATUS":[{"STATUS":"S"}],"DEVS":[{"Temperature":65.5}]}`
+	})
+
+	devs, err := client.Devs()
+	if err != nil {
+		t.Fatalf("Devs: %v", err)
+	}
+	if len(devs) != 1 || devs[0].Temperature != 65.5 {
+		t.Errorf("Devs() = %+v, want one dev with Temperature 65.5", devs)
+	}
+}
+
+func TestVersionErrNoData(t *testing.T) {
+	client := fakeMiner(t, func(cmd APICommand) string {
+		return `{"STATUS":[{"STATUS":"S"}]}`
+	})
+
+	if _, err := client.Version(); err == nil {
+		t.Error("Version() with no VERSION section: want error, got nil")
+	}
+}
+
+func TestAddPoolSendsPrivileged(t *testing.T) {
+	var gotMethod, gotParameter string
+	client := fakeMiner(t, func(cmd APICommand) string {
+		gotMethod, gotParameter = cmd.Method, cmd.Parameter
+		return `{"STATUS":[{"STATUS":"S"}]}`
+	})
+
+	if err := client.AddPool("stratum+tcp://pool:3333", "user", "pass"); err != nil {
+		t.Fatalf("AddPool: %v", err)
+	}
+	if gotMethod != "addpool" {
+		t.Errorf("method = %q, want %q", gotMethod, "addpool")
+	}
+	want := "stratum+tcp://pool:3333,user,pass"
+	if gotParameter != want {
+		t.Errorf("parameter = %q, want %q", gotParameter, want)
+	}
+}
+
+func TestSwitchPoolIsNotPrivileged(t *testing.T) {
+	calls := 0
+	client := fakeMiner(t, func(cmd APICommand) string {
+		calls++
+		if cmd.Method != "switchpool" || cmd.Parameter != "2" {
+			t.Errorf("sent method=%q parameter=%q, want switchpool/2", cmd.Method, cmd.Parameter)
+		}
+		return `{"STATUS":[{"STATUS":"S"}]}`
+	})
+	client.Password = "hunter2"
+
+	if err := client.SwitchPool(2); err != nil {
+		t.Fatalf("SwitchPool: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("miner saw %d commands, want 1 (SwitchPool isn't privileged)", calls)
+	}
+}
@@ -0,0 +1,164 @@
+package cgminerapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Driver translates cgminerapi's canonical requests into the command and
+// parameter shape a particular firmware expects, and decodes its response
+// back into the canonical types. Vendor forks of cgminer (Antminer,
+// Innosilicon, Whatsminer, ...) rename fields and add commands; a Driver is
+// where that dialect lives so APIClient's typed methods don't need to know
+// about it.
+type Driver interface {
+	// Name identifies the driver, e.g. "antminer-s9".
+	Name() string
+	// Probe reports whether a miner that returned version and stats is
+	// running this driver's firmware.
+	Probe(version Version, stats []ChipStats) bool
+	// Summary runs this driver's version of the "summary" command.
+	Summary(client *APIClient) (Summary, error)
+	// ChipStat runs this driver's version of the per-device stats command
+	// for device n.
+	ChipStat(client *APIClient, n int) (ChipStats, error)
+}
+
+// GenericCGMiner talks stock, unforked cgminer. It is the default Driver for
+// an APIClient with no Driver set, and every vendor Driver below embeds it to
+// reuse its Summary implementation and, where a fork hasn't actually changed
+// the "stats" shape, its ChipStat implementation too (see Whatsminer for a
+// fork that has).
+type GenericCGMiner struct{}
+
+// Name implements Driver.
+func (GenericCGMiner) Name() string { return "cgminer" }
+
+// Probe implements Driver. GenericCGMiner is the fallback driver, so it
+// matches anything.
+func (GenericCGMiner) Probe(Version, []ChipStats) bool { return true }
+
+// Summary implements Driver.
+func (GenericCGMiner) Summary(client *APIClient) (Summary, error) {
+	resp, err := client.Send(&APICommand{Method: "summary"})
+	if err != nil {
+		return Summary{}, err
+	}
+	if len(resp.Summary) == 0 {
+		return Summary{}, errNoData("summary", "SUMMARY")
+	}
+	return resp.Summary[0], nil
+}
+
+// ChipStat implements Driver.
+func (GenericCGMiner) ChipStat(client *APIClient, n int) (ChipStats, error) {
+	resp, err := client.Send(&APICommand{Method: "stats", Parameter: fmt.Sprint(n)})
+	if err != nil {
+		return ChipStats{}, err
+	}
+	if len(resp.Stats) == 0 {
+		return ChipStats{}, errNoData("stats", "STATS")
+	}
+	return resp.Stats[0], nil
+}
+
+// probeType reports whether version or any stats entry's Name mentions one
+// of needles, which is how the vendor forks below identify themselves in
+// the absence of a dedicated "what model am I" command.
+func probeType(version Version, stats []ChipStats, needles ...string) bool {
+	haystacks := []string{version.Type, version.Miner}
+	for _, s := range stats {
+		haystacks = append(haystacks, s.Name)
+	}
+	for _, h := range haystacks {
+		for _, n := range needles {
+			if strings.Contains(strings.ToLower(h), strings.ToLower(n)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AntminerS9 talks Bitmain's Antminer S9 firmware. Its Summary/ChipStat wire
+// shape hasn't been found to diverge from stock cgminer's, so it only adds
+// detection on top of GenericCGMiner; see Whatsminer for a fork whose
+// "stats" shape does diverge and gets translated.
+type AntminerS9 struct{ GenericCGMiner }
+
+// Name implements Driver.
+func (AntminerS9) Name() string { return "antminer-s9" }
+
+// Probe implements Driver.
+func (AntminerS9) Probe(version Version, stats []ChipStats) bool {
+	return probeType(version, stats, "antminer s9")
+}
+
+// AntminerL3Plus talks Bitmain's Antminer L3+ firmware; detection-only, see AntminerS9.
+type AntminerL3Plus struct{ GenericCGMiner }
+
+// Name implements Driver.
+func (AntminerL3Plus) Name() string { return "antminer-l3+" }
+
+// Probe implements Driver.
+func (AntminerL3Plus) Probe(version Version, stats []ChipStats) bool {
+	return probeType(version, stats, "antminer l3+")
+}
+
+// AntminerT9 talks Bitmain's Antminer T9 firmware; detection-only, see AntminerS9.
+type AntminerT9 struct{ GenericCGMiner }
+
+// Name implements Driver.
+func (AntminerT9) Name() string { return "antminer-t9" }
+
+// Probe implements Driver.
+func (AntminerT9) Probe(version Version, stats []ChipStats) bool {
+	return probeType(version, stats, "antminer t9")
+}
+
+// InnosiliconT3 talks Innosilicon's T3 firmware; detection-only, see AntminerS9.
+type InnosiliconT3 struct{ GenericCGMiner }
+
+// Name implements Driver.
+func (InnosiliconT3) Name() string { return "innosilicon-t3" }
+
+// Probe implements Driver.
+func (InnosiliconT3) Probe(version Version, stats []ChipStats) bool {
+	return probeType(version, stats, "t3")
+}
+
+// knownDrivers lists every vendor Driver DetectDriver probes, in order, most
+// specific first; GenericCGMiner is tried last as the catch-all.
+var knownDrivers = []Driver{
+	AntminerS9{},
+	AntminerL3Plus{},
+	AntminerT9{},
+	InnosiliconT3{},
+	Whatsminer{},
+	GenericCGMiner{},
+}
+
+// DetectDriver probes client against the known Driver implementations by
+// running Version and Stats against it, and returns the first match. It
+// falls back to GenericCGMiner if none of the vendor drivers claim it, or if
+// the probe commands fail.
+func DetectDriver(client *APIClient) Driver {
+	version, _ := client.Version()
+	stats, _ := client.Stats()
+	for _, d := range knownDrivers {
+		if d.Probe(version, stats) {
+			return d
+		}
+	}
+	return GenericCGMiner{}
+}
+
+// NewCgminerAPIAutoDetect returns a pointer to an APIClient with the
+// specified host and port, probing the miner once via DetectDriver to pin
+// the correct Driver for vendor-forked firmware. Use NewCgminerAPI and set
+// Driver directly to skip the probe and pin one yourself.
+func NewCgminerAPIAutoDetect(host, port string) *APIClient {
+	client := NewCgminerAPI(host, port)
+	client.Driver = DetectDriver(client)
+	return client
+}
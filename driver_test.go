@@ -0,0 +1,122 @@
+package cgminerapi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProbeType(t *testing.T) {
+	cases := []struct {
+		name    string
+		version Version
+		stats   []ChipStats
+		needles []string
+		want    bool
+	}{
+		{
+			name:    "match on version Type",
+			version: Version{Type: "Antminer S9"},
+			needles: []string{"antminer s9"},
+			want:    true,
+		},
+		{
+			name:    "match on version Miner",
+			version: Version{Miner: "whatsminer-m20"},
+			needles: []string{"whatsminer"},
+			want:    true,
+		},
+		{
+			name:    "match on stats Name",
+			stats:   []ChipStats{{Name: "innosilicon-t3"}},
+			needles: []string{"t3"},
+			want:    true,
+		},
+		{
+			name:    "case insensitive",
+			version: Version{Type: "ANTMINER T9"},
+			needles: []string{"antminer t9"},
+			want:    true,
+		},
+		{
+			name:    "no match",
+			version: Version{Type: "cgminer"},
+			stats:   []ChipStats{{Name: "cgminer"}},
+			needles: []string{"antminer s9"},
+			want:    false,
+		},
+	}
+
+	for _, c := range cases {
+		if got := probeType(c.version, c.stats, c.needles...); got != c.want {
+			t.Errorf("%s: probeType() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+// TestKnownDriversOrder mirrors DetectDriver's probe loop without requiring a
+// live connection, pinning that more specific vendor drivers are tried before
+// GenericCGMiner falls back to matching everything.
+func TestKnownDriversOrder(t *testing.T) {
+	probe := func(version Version, stats []ChipStats) Driver {
+		for _, d := range knownDrivers {
+			if d.Probe(version, stats) {
+				return d
+			}
+		}
+		return GenericCGMiner{}
+	}
+
+	cases := []struct {
+		name    string
+		version Version
+		stats   []ChipStats
+		want    string
+	}{
+		{"antminer s9", Version{Type: "Antminer S9"}, nil, "antminer-s9"},
+		{"antminer l3+", Version{Type: "Antminer L3+"}, nil, "antminer-l3+"},
+		{"antminer t9", Version{Type: "Antminer T9"}, nil, "antminer-t9"},
+		{"innosilicon t3", Version{Type: "Innosilicon T3"}, nil, "innosilicon-t3"},
+		{"whatsminer", Version{Type: "Whatsminer M20"}, nil, "whatsminer"},
+		{"unknown falls back to generic", Version{Type: "some other miner"}, nil, "cgminer"},
+	}
+
+	for _, c := range cases {
+		if got := probe(c.version, c.stats); got.Name() != c.want {
+			t.Errorf("%s: matched driver %q, want %q", c.name, got.Name(), c.want)
+		}
+	}
+}
+
+func TestWhatsminerCanonicalize(t *testing.T) {
+	fields := map[string]json.RawMessage{
+		"ASC":           json.RawMessage(`0`),
+		"ID":            json.RawMessage(`"0"`),
+		"Elapsed":       json.RawMessage(`"123.5"`),
+		"Name":          json.RawMessage(`"whatsminer"`),
+		"Chip1 Accept":  json.RawMessage(`154`),
+		"Chip1 Reject":  json.RawMessage(`2`),
+		"Chip1 Noise":   json.RawMessage(`0`),
+		"Chain Rate1":   json.RawMessage(`4.71`),
+		"Temp1_1":       json.RawMessage(`63`),
+		"Temp1_2":       json.RawMessage(`65`),
+		"frequency":     json.RawMessage(`625`),
+		"miner_version": json.RawMessage(`"1.2.3"`),
+	}
+
+	got := whatsminerCanonicalize(fields)
+
+	want := []string{"ASC", "ID", "Elapsed", "Name", "1_accept", "1_reject", "1_noise", "chain_rate1", "temp1_1", "temp1_2"}
+	for _, key := range want {
+		if _, ok := got[key]; !ok {
+			t.Errorf("whatsminerCanonicalize() missing key %q, got %v", key, got)
+		}
+	}
+	for _, dropped := range []string{"frequency", "miner_version"} {
+		if _, ok := got[dropped]; ok {
+			t.Errorf("whatsminerCanonicalize() kept unrecognized key %q, want dropped", dropped)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("whatsminerCanonicalize() = %d keys, want %d", len(got), len(want))
+	}
+}
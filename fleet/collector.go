@@ -0,0 +1,109 @@
+package fleet
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	hashrateDesc = prometheus.NewDesc(
+		"cgminer_hashrate_mhs", "Reported hashrate in MH/s.",
+		[]string{"rig", "location", "model", "dev", "window"}, nil)
+	hwErrorsDesc = prometheus.NewDesc(
+		"cgminer_hw_errors_total", "Cumulative hardware error count.",
+		[]string{"rig", "location", "model"}, nil)
+	acceptedDesc = prometheus.NewDesc(
+		"cgminer_accepted_total", "Cumulative accepted share count.",
+		[]string{"rig", "location", "model"}, nil)
+	rejectedDesc = prometheus.NewDesc(
+		"cgminer_rejected_total", "Cumulative rejected share count.",
+		[]string{"rig", "location", "model"}, nil)
+	temperatureDesc = prometheus.NewDesc(
+		"cgminer_temperature_celsius", "Device temperature in degrees Celsius.",
+		[]string{"rig", "location", "model", "dev"}, nil)
+	fanDesc = prometheus.NewDesc(
+		"cgminer_fan_rpm", "Device fan speed in RPM.",
+		[]string{"rig", "location", "model", "dev"}, nil)
+	poolActiveDesc = prometheus.NewDesc(
+		"cgminer_pool_stratum_active", "Whether the pool's stratum connection is active.",
+		[]string{"rig", "location", "model", "pool"}, nil)
+	lastShareDesc = prometheus.NewDesc(
+		"cgminer_last_share_seconds", "Time of the pool's last accepted share, in seconds since the epoch.",
+		[]string{"rig", "location", "model", "pool"}, nil)
+	upDesc = prometheus.NewDesc(
+		"up", "Whether the last poll of the target succeeded.",
+		[]string{"rig", "location", "model"}, nil)
+
+	allDescs = []*prometheus.Desc{
+		hashrateDesc, hwErrorsDesc, acceptedDesc, rejectedDesc,
+		temperatureDesc, fanDesc, poolActiveDesc, lastShareDesc, upDesc,
+	}
+)
+
+// Collector adapts a Fleet to the prometheus.Collector interface. Unlike
+// Fleet.Run, it polls every registered target once per Collect call (i.e.
+// once per scrape) rather than on the Fleet's own Interval.
+type Collector struct {
+	fleet *Fleet
+}
+
+// NewCollector returns a prometheus.Collector that polls f's targets on
+// demand, once per scrape.
+func NewCollector(f *Fleet) *Collector {
+	return &Collector{fleet: f}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range allDescs {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector. A target that fails to respond
+// still yields an "up" metric of 0, rather than being dropped or causing
+// Collect to fail outright.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, t := range c.fleet.targets {
+		for _, s := range c.fleet.poll(t) {
+			desc, labelValues := describe(s)
+			if desc == nil {
+				continue
+			}
+			valueType := prometheus.GaugeValue
+			if strings.HasSuffix(s.Metric, "_total") {
+				valueType = prometheus.CounterValue
+			}
+			ch <- prometheus.MustNewConstMetric(desc, valueType, s.Value, labelValues...)
+		}
+	}
+}
+
+// describe maps a Sample to its Desc and the label values in the order that
+// Desc declares them.
+func describe(s Sample) (*prometheus.Desc, []string) {
+	base := []string{s.Labels["rig"], s.Labels["location"], s.Labels["model"]}
+
+	switch s.Metric {
+	case "cgminer_hashrate_mhs":
+		return hashrateDesc, append(base, s.Labels["dev"], s.Labels["window"])
+	case "cgminer_hw_errors_total":
+		return hwErrorsDesc, base
+	case "cgminer_accepted_total":
+		return acceptedDesc, base
+	case "cgminer_rejected_total":
+		return rejectedDesc, base
+	case "cgminer_temperature_celsius":
+		return temperatureDesc, append(base, s.Labels["dev"])
+	case "cgminer_fan_rpm":
+		return fanDesc, append(base, s.Labels["dev"])
+	case "cgminer_pool_stratum_active":
+		return poolActiveDesc, append(base, s.Labels["pool"])
+	case "cgminer_last_share_seconds":
+		return lastShareDesc, append(base, s.Labels["pool"])
+	case "up":
+		return upDesc, base
+	}
+	return nil, nil
+}
@@ -0,0 +1,196 @@
+/*
+Package fleet polls a set of cgminer-compatible miners on an interval and
+turns their Summary/Devs/Pools responses into flat, labeled Samples.
+
+Register each miner as a Target, then either drain Run's channel directly or
+wrap the Fleet in a Collector and register that with a Prometheus registry:
+
+	f := fleet.NewFleet(30 * time.Second)
+	f.Register(fleet.Target{Host: "10.0.0.11", Port: "4028", Rig: "rig-1", Model: "Antminer S9"})
+	prometheus.MustRegister(fleet.NewCollector(f))
+*/
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/dstaley/cgminerapi"
+)
+
+// Target identifies a single miner to poll, along with the labels used to
+// identify it in emitted metrics.
+type Target struct {
+	Host, Port, Password string
+	Rig, Location, Model string
+}
+
+// Sample is a single metric reading for a Target.
+type Sample struct {
+	Metric string
+	Labels map[string]string
+	Value  float64
+}
+
+// Fleet polls a set of miners on a configurable interval and emits Sample
+// readings over a channel. See Collector for a pull-based alternative that
+// polls on demand instead, for registration with a Prometheus registry.
+type Fleet struct {
+	// Interval is how often each target is polled by Run.
+	Interval time.Duration
+	// Jitter bounds the random delay added before each target's first poll,
+	// so a fleet of hundreds of miners doesn't stampede the network all at
+	// once. Defaults to Interval/10 in NewFleet.
+	Jitter time.Duration
+	// Timeout bounds each dial, write and read of a target's poll; it sets the
+	// client's DialTimeout/ReadTimeout/WriteTimeout. A poll runs three
+	// commands, so the poll as a whole can take longer. Zero means no timeout.
+	Timeout time.Duration
+
+	targets []Target
+}
+
+// NewFleet returns a Fleet that polls its targets every interval.
+func NewFleet(interval time.Duration) *Fleet {
+	return &Fleet{Interval: interval, Jitter: interval / 10}
+}
+
+// Register adds a target to the fleet.
+func (f *Fleet) Register(t Target) {
+	f.targets = append(f.targets, t)
+}
+
+// Run polls every registered target once per Interval, sending a Sample on
+// the returned channel for each metric reading. It stops and closes the
+// channel when ctx is cancelled.
+func (f *Fleet) Run(ctx context.Context) <-chan Sample {
+	out := make(chan Sample)
+
+	var wg sync.WaitGroup
+	for _, t := range f.targets {
+		wg.Add(1)
+		go func(t Target) {
+			defer wg.Done()
+			f.runTarget(ctx, t, out)
+		}(t)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+func (f *Fleet) runTarget(ctx context.Context, t Target, out chan<- Sample) {
+	if f.Jitter > 0 {
+		select {
+		case <-time.After(time.Duration(rand.Int63n(int64(f.Jitter)))):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	ticker := time.NewTicker(f.Interval)
+	defer ticker.Stop()
+
+	for {
+		for _, s := range f.poll(t) {
+			select {
+			case out <- s:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// poll fetches Summary, Devs and Pools from t and flattens them into
+// Samples. A failure to reach the miner at any stage is reflected as an "up"
+// sample of 0 rather than returned as an error, so one unreachable target
+// can't stop the others from being polled.
+func (f *Fleet) poll(t Target) []Sample {
+	client := cgminerapi.NewCgminerAPI(t.Host, t.Port)
+	client.Password = t.Password
+	if f.Timeout > 0 {
+		client.DialTimeout = f.Timeout
+		client.ReadTimeout = f.Timeout
+		client.WriteTimeout = f.Timeout
+	}
+
+	base := map[string]string{"rig": t.Rig, "location": t.Location, "model": t.Model}
+	up := 1.0
+	var samples []Sample
+
+	if summary, err := client.Summary(); err != nil {
+		up = 0
+	} else {
+		samples = append(samples,
+			sample("cgminer_hashrate_mhs", withLabels(base, "dev", "total", "window", "5s"), summary.MHS5s),
+			sample("cgminer_hashrate_mhs", withLabels(base, "dev", "total", "window", "avg"), summary.MHSav),
+			sample("cgminer_hw_errors_total", base, summary.HardwareErrors),
+			sample("cgminer_accepted_total", base, float64(summary.Accepted)),
+			sample("cgminer_rejected_total", base, summary.Rejected),
+		)
+	}
+
+	if devs, err := client.Devs(); err != nil {
+		up = 0
+	} else {
+		for i, d := range devs {
+			dev := fmt.Sprint(i)
+			samples = append(samples,
+				sample("cgminer_hashrate_mhs", withLabels(base, "dev", dev, "window", "5s"), d.MHS5s),
+				sample("cgminer_hashrate_mhs", withLabels(base, "dev", dev, "window", "avg"), d.MHSav),
+				sample("cgminer_temperature_celsius", withLabels(base, "dev", dev), d.Temperature),
+				sample("cgminer_fan_rpm", withLabels(base, "dev", dev), float64(d.FanSpeed)),
+			)
+		}
+	}
+
+	if pools, err := client.Pools(); err != nil {
+		up = 0
+	} else {
+		for i, p := range pools {
+			pool := fmt.Sprint(i)
+			active := 0.0
+			if p.StratumActive {
+				active = 1
+			}
+			samples = append(samples,
+				sample("cgminer_pool_stratum_active", withLabels(base, "pool", pool), active),
+				sample("cgminer_last_share_seconds", withLabels(base, "pool", pool), p.LastShareTime),
+			)
+		}
+	}
+
+	samples = append(samples, sample("up", base, up))
+	return samples
+}
+
+func sample(metric string, labels map[string]string, value float64) Sample {
+	return Sample{Metric: metric, Labels: labels, Value: value}
+}
+
+// withLabels returns a copy of base with the given key/value pairs added,
+// leaving base untouched so it can be reused across samples.
+func withLabels(base map[string]string, kv ...string) map[string]string {
+	labels := make(map[string]string, len(base)+len(kv)/2)
+	for k, v := range base {
+		labels[k] = v
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		labels[kv[i]] = kv[i+1]
+	}
+	return labels
+}
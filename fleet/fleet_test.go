@@ -0,0 +1,33 @@
+package fleet
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestRunClosesChannelAfterCancelWithoutPanic reproduces the panic from
+// closing out while a runTarget goroutine was still selecting on a send to
+// it: a fleet of targets that keep polling and sending Samples, cancelled
+// mid-flight, must not panic and must eventually close out.
+func TestRunClosesChannelAfterCancelWithoutPanic(t *testing.T) {
+	f := NewFleet(time.Millisecond)
+	f.Jitter = 0
+	// Port 1 is unreachable on loopback; poll fails fast but runTarget still
+	// has to go around its loop and select against ctx.Done() repeatedly.
+	for i := 0; i < 50; i++ {
+		f.Register(Target{Host: "127.0.0.1", Port: "1", Rig: "rig"})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := f.Run(ctx)
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+	}()
+
+	for range out {
+		// drain; poll failures still emit an "up" Sample per target.
+	}
+}
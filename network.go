@@ -0,0 +1,299 @@
+package cgminerapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+)
+
+// APIClient stores connection details and per-request timeouts for talking
+// to a cgminer RPC endpoint.
+//
+// By default every Send/SendContext call dials a fresh TCP connection, as
+// cgminer expects. Set KeepAlive to keep the connection open between calls
+// and try it first on the next one; Close releases it. Responses are read
+// until the miner closes its end, so against stock cgminer this saves no
+// dial (see KeepAlive).
+type APIClient struct {
+	Host, Port string
+
+	// DialTimeout bounds how long Dial may take. Zero means no timeout.
+	DialTimeout time.Duration
+	// ReadTimeout bounds how long a single read may take. Zero means no
+	// timeout beyond the context passed to SendContext, if any.
+	ReadTimeout time.Duration
+	// WriteTimeout bounds how long a single write may take. Zero means no
+	// timeout beyond the context passed to SendContext, if any.
+	WriteTimeout time.Duration
+	// KeepAlive, when true, keeps the connection open after a call and tries
+	// it first on the next one instead of dialing. Since cgminer closes its
+	// end of the connection after every response, a reused connection is
+	// stale: writing the next command to it either fails or succeeds
+	// locally and then reads back an empty response. SendContext redials
+	// and retries once in either case, so callers don't see it. A failure
+	// partway through reading a response is never retried, since the
+	// command may already have reached the miner by then.
+	KeepAlive bool
+
+	// Password authenticates privileged commands (addpool, restart, save,
+	// ...) via cgminer's token challenge/response scheme. Leave empty for
+	// miners started without --api-allow W: or an equivalent password.
+	Password string
+
+	// Driver translates Summary/ChipStat into the command and parameter
+	// shape a vendor-forked firmware expects. Leave nil to use stock
+	// cgminer's shape (GenericCGMiner), or set it directly to pin a driver;
+	// see DetectDriver to probe for one instead.
+	Driver Driver
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// driver returns client.Driver, or GenericCGMiner if none is set.
+func (client *APIClient) driver() Driver {
+	if client.Driver != nil {
+		return client.Driver
+	}
+	return GenericCGMiner{}
+}
+
+// NewCgminerAPI returns a pointer to an APIClient with the specified host and port.
+func NewCgminerAPI(host string, port string) *APIClient {
+	return &APIClient{Host: host, Port: port}
+}
+
+// Close releases the connection held open by KeepAlive, if any. It is a
+// no-op if KeepAlive is unset or no connection has been established yet.
+func (client *APIClient) Close() error {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.conn == nil {
+		return nil
+	}
+	err := client.conn.Close()
+	client.conn = nil
+	return err
+}
+
+func ReadAll(r io.Reader) (string, error) {
+	b, err := ioutil.ReadAll(r)
+	return string(bytes.Trim(b, " \x00")), err
+}
+
+// Send sends the APICommand (and any specified parameters) and returns a
+// Response containing the response from the API. It is equivalent to
+// SendContext with context.Background.
+func (client *APIClient) Send(command *APICommand) (Response, error) {
+	return client.SendContext(context.Background(), command)
+}
+
+// SendContext sends the APICommand and returns a Response containing the
+// response from the API. ctx bounds the entire round trip: if it is
+// cancelled or its deadline expires while a dial, write, or read is in
+// flight, the underlying connection is closed to unblock it.
+//
+// Stock cgminer closes its end of the connection after every response, so a
+// KeepAlive connection reused from a previous call is always stale by the
+// time the next command runs. SendContext redials and retries once when that
+// staleness shows up as a failure to write the command or as an empty
+// response, rather than surfacing it to the caller; a failure while reading
+// the response is returned as-is, since the command may have already
+// reached the miner.
+func (client *APIClient) SendContext(ctx context.Context, command *APICommand) (Response, error) {
+	body, err := client.send(ctx, command)
+	if err != nil {
+		return Response{}, err
+	}
+	var resp Response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return Response{}, err
+	}
+	return resp, nil
+}
+
+// sendSection runs command and returns the raw bytes of its named top-level
+// response section (e.g. "STATS"), without unmarshaling that section into a
+// typed struct. This lets a Driver rewrite vendor-specific field names
+// before handing the section to the canonical decoder.
+func (client *APIClient) sendSection(command *APICommand, section string) (json.RawMessage, error) {
+	body, err := client.send(context.Background(), command)
+	if err != nil {
+		return nil, err
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(body, &sections); err != nil {
+		return nil, err
+	}
+	return sections[section], nil
+}
+
+// send runs command and returns the raw bytes of its response, retrying
+// once against a fresh connection if a reused KeepAlive connection turns out
+// to be stale. The retry only happens when the failure is known to have
+// occurred before command reached the miner (see errNotSent); once a command
+// has been written, resending it risks applying a non-idempotent privileged
+// command (AddPool, Save, ...) a second time, so a failure partway through
+// reading the response is returned to the caller instead of retried.
+func (client *APIClient) send(ctx context.Context, command *APICommand) ([]byte, error) {
+	c, reused, err := client.getConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := client.doOnce(ctx, c, command)
+	if err == nil || !reused || ctx.Err() != nil {
+		return body, err
+	}
+	var notSent *errNotSent
+	if !errors.As(err, &notSent) {
+		return body, err
+	}
+
+	c, err = client.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if client.KeepAlive {
+		client.mu.Lock()
+		client.conn = c
+		client.mu.Unlock()
+	}
+	return client.doOnce(ctx, c, command)
+}
+
+// doOnce runs a single round trip over c, closing c if ctx is cancelled
+// while it's in flight.
+func (client *APIClient) doOnce(ctx context.Context, c net.Conn, command *APICommand) ([]byte, error) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-done:
+		}
+	}()
+
+	body, err := client.transact(c, command)
+	if err != nil {
+		// c may be broken (closed by the miner, by the goroutine above, or
+		// otherwise); drop it from the KeepAlive cache so the next call
+		// redials instead of reusing it.
+		client.forgetConn(c)
+		c.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	if !client.KeepAlive {
+		c.Close()
+	}
+	return body, nil
+}
+
+// forgetConn clears client.conn if it currently points at c, so a later
+// call redials instead of reusing a connection known to be broken.
+func (client *APIClient) forgetConn(c net.Conn) {
+	client.mu.Lock()
+	if client.conn == c {
+		client.conn = nil
+	}
+	client.mu.Unlock()
+}
+
+// getConn returns the connection to use for the next command, dialing one
+// if KeepAlive is unset or no connection has been established yet.
+func (client *APIClient) getConn(ctx context.Context) (c net.Conn, reused bool, err error) {
+	if client.KeepAlive {
+		client.mu.Lock()
+		if client.conn != nil {
+			c := client.conn
+			client.mu.Unlock()
+			return c, true, nil
+		}
+		client.mu.Unlock()
+	}
+
+	c, err = client.dial(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if client.KeepAlive {
+		client.mu.Lock()
+		client.conn = c
+		client.mu.Unlock()
+	}
+	return c, false, nil
+}
+
+func (client *APIClient) dial(ctx context.Context) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: client.DialTimeout}
+	return dialer.DialContext(ctx, "tcp", client.Host+":"+client.Port)
+}
+
+// errNotSent wraps an error that occurred before command reached the wire
+// (marshaling it or writing it to the connection), so a caller knows the
+// miner can't have seen command and it's safe to retry against a fresh
+// connection. It also wraps an empty response: on a reused KeepAlive
+// connection that means the miner had closed its end before command was
+// written. Any other error after the write succeeds isn't wrapped, since the
+// miner may already have processed the command even though transact didn't
+// get to see its response.
+type errNotSent struct{ err error }
+
+func (e *errNotSent) Error() string { return e.err.Error() }
+func (e *errNotSent) Unwrap() error { return e.err }
+
+// transact writes command to c and returns the raw bytes of its response,
+// after checking that the response carries a non-empty, non-error STATUS.
+func (client *APIClient) transact(c net.Conn, command *APICommand) ([]byte, error) {
+	blob, err := json.Marshal(command)
+	if err != nil {
+		return nil, &errNotSent{err}
+	}
+
+	if client.WriteTimeout > 0 {
+		c.SetWriteDeadline(time.Now().Add(client.WriteTimeout))
+	}
+	if _, err := c.Write(blob); err != nil {
+		return nil, &errNotSent{err}
+	}
+
+	if client.ReadTimeout > 0 {
+		c.SetReadDeadline(time.Now().Add(client.ReadTimeout))
+	}
+	jsonstring, err := ReadAll(c)
+	if err != nil {
+		return nil, err
+	}
+	if jsonstring == "" {
+		return nil, &errNotSent{errors.New("cgminerapi: connection closed without a response")}
+	}
+
+	var status struct {
+		Status []APIStatus `json:"STATUS"`
+	}
+	if err := json.Unmarshal([]byte(jsonstring), &status); err != nil {
+		return nil, err
+	}
+	if len(status.Status) == 0 {
+		return nil, errors.New("cgminerapi: response had no STATUS section")
+	}
+	switch status.Status[0].STATUS {
+	case "W", "I", "S":
+		return []byte(jsonstring), nil
+	case "E", "F":
+		return nil, errors.New(status.Status[0].Msg)
+	}
+	return nil, errors.New("cgminerapi: unknown STATUS " + status.Status[0].STATUS)
+}
@@ -0,0 +1,107 @@
+package cgminerapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeConn is a net.Conn whose Write/Read behavior is fixed up front, so
+// send's retry logic can be driven without racing a real socket's timing.
+type fakeConn struct {
+	writeErr error
+	readErr  error
+	closed   bool
+}
+
+func (c *fakeConn) Read(b []byte) (int, error) {
+	if c.readErr != nil {
+		return 0, c.readErr
+	}
+	return 0, io.EOF
+}
+
+func (c *fakeConn) Write(b []byte) (int, error) {
+	if c.writeErr != nil {
+		return 0, c.writeErr
+	}
+	return len(b), nil
+}
+
+func (c *fakeConn) Close() error                       { c.closed = true; return nil }
+func (c *fakeConn) LocalAddr() net.Addr                { return nil }
+func (c *fakeConn) RemoteAddr() net.Addr               { return nil }
+func (c *fakeConn) SetDeadline(t time.Time) error      { return nil }
+func (c *fakeConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *fakeConn) SetWriteDeadline(t time.Time) error { return nil }
+
+func TestSendRetriesWhenWriteFails(t *testing.T) {
+	calls := 0
+	client := fakeMiner(t, func(cmd APICommand) string {
+		calls++
+		return `{"STATUS":[{"STATUS":"S"}]}`
+	})
+	client.KeepAlive = true
+	client.conn = &fakeConn{writeErr: errors.New("broken pipe")}
+
+	body, err := client.send(context.Background(), &APICommand{Method: "summary"})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("fakeMiner saw %d commands, want 1 (the stale conn never reaches it)", calls)
+	}
+	if len(body) == 0 {
+		t.Error("send() returned an empty body")
+	}
+}
+
+func TestSendDoesNotRetryAfterWriteSucceeds(t *testing.T) {
+	// Port 1 is unreachable on loopback, so a redial attempt would surface
+	// as a dial error distinct from readErr below.
+	client := NewCgminerAPI("127.0.0.1", "1")
+	client.KeepAlive = true
+	client.conn = &fakeConn{readErr: errors.New("connection reset by peer")}
+
+	_, err := client.send(context.Background(), &APICommand{Method: "summary"})
+	if err == nil {
+		t.Fatal("send(): want the read failure surfaced, got nil")
+	}
+	if !strings.Contains(err.Error(), "connection reset by peer") {
+		t.Errorf("send() error = %v, want the original read failure (no redial attempted)", err)
+	}
+	if client.conn != nil {
+		t.Error("the broken connection should be forgotten, not reused again")
+	}
+}
+
+func TestSendContextCancelledClosesConnection(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// Never respond, so SendContext only returns via ctx cancellation.
+		<-make(chan struct{})
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	client := NewCgminerAPI(host, port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.SendContext(ctx, &APICommand{Method: "summary"}); err == nil {
+		t.Error("SendContext(): want a context error, got nil")
+	}
+}
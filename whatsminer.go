@@ -0,0 +1,84 @@
+package cgminerapi
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// Whatsminer talks MicroBT's Whatsminer firmware. Its "stats" command
+// ignores the per-device parameter GenericCGMiner sends and instead returns
+// every device when given "all"; the per-chip/per-chain fields it reports
+// are also named differently than stock cgminer ("Chain Rate1", "Temp1_1",
+// "Chip1 Accept" instead of "chain_rate1", "temp1_1", "1_accept"). ChipStat
+// translates both: it requests "all" and rewrites the recognized field
+// names to the canonical form before handing the entry to ChipStats'
+// unmarshaler, so the per-chip/per-chain maps populate the same way they
+// would from stock cgminer.
+type Whatsminer struct{ GenericCGMiner }
+
+// Name implements Driver.
+func (Whatsminer) Name() string { return "whatsminer" }
+
+// Probe implements Driver.
+func (Whatsminer) Probe(version Version, stats []ChipStats) bool {
+	return probeType(version, stats, "whatsminer")
+}
+
+var (
+	whatsminerChainRate   = regexp.MustCompile(`^Chain Rate(\d+)$`)
+	whatsminerChainTemp   = regexp.MustCompile(`^Temp(\d+)_(\d+)$`)
+	whatsminerChipCounter = regexp.MustCompile(`^Chip(\d+) (Accept|Reject|Noise)$`)
+)
+
+// ChipStat implements Driver.
+func (w Whatsminer) ChipStat(client *APIClient, n int) (ChipStats, error) {
+	raw, err := client.sendSection(&APICommand{Method: "stats", Parameter: "all"}, "STATS")
+	if err != nil {
+		return ChipStats{}, err
+	}
+
+	var entries []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return ChipStats{}, err
+	}
+	if n < 0 || n >= len(entries) {
+		return ChipStats{}, errNoData("stats", "STATS")
+	}
+
+	blob, err := json.Marshal(whatsminerCanonicalize(entries[n]))
+	if err != nil {
+		return ChipStats{}, err
+	}
+
+	var stat ChipStats
+	if err := json.Unmarshal(blob, &stat); err != nil {
+		return ChipStats{}, err
+	}
+	return stat, nil
+}
+
+// whatsminerCanonicalize rewrites a single Whatsminer STATS entry's field
+// names into the "<chip>_accept" / "chain_rate<chain>" / "temp<chain>_<chip>"
+// shape ChipStats.UnmarshalJSON recognizes. Fields it doesn't recognize
+// (frequency, voltage, chain_acs, miner_version, ...) are dropped, the same
+// as ChipStats drops any other unfamiliar alias.
+func whatsminerCanonicalize(fields map[string]json.RawMessage) map[string]json.RawMessage {
+	out := make(map[string]json.RawMessage, len(fields))
+	for key, value := range fields {
+		switch {
+		case key == "ASC" || key == "PGA" || key == "ID" || key == "Elapsed" || key == "Name":
+			out[key] = value
+		case whatsminerChainRate.MatchString(key):
+			m := whatsminerChainRate.FindStringSubmatch(key)
+			out["chain_rate"+m[1]] = value
+		case whatsminerChainTemp.MatchString(key):
+			m := whatsminerChainTemp.FindStringSubmatch(key)
+			out["temp"+m[1]+"_"+m[2]] = value
+		case whatsminerChipCounter.MatchString(key):
+			m := whatsminerChipCounter.FindStringSubmatch(key)
+			out[m[1]+"_"+strings.ToLower(m[2])] = value
+		}
+	}
+	return out
+}